@@ -0,0 +1,157 @@
+package bitballoon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultBaseURL = "https://www.bitballoon.com/api/v1/"
+
+// Client is the root of the API: it holds the HTTP transport and base URL
+// shared by every service, and exposes each resource (currently just Sites)
+// as a field so callers write client.Sites.Get(id) rather than constructing
+// services themselves.
+type Client struct {
+	client      *http.Client
+	BaseURL     *url.URL
+	AccessToken string
+
+	Sites *SitesService
+}
+
+// NewClient returns a Client authenticated with accessToken, ready to use.
+func NewClient(accessToken string) *Client {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	c := &Client{
+		client:      http.DefaultClient,
+		BaseURL:     baseURL,
+		AccessToken: accessToken,
+	}
+	c.Sites = &SitesService{client: c}
+
+	return c
+}
+
+// Response wraps the raw *http.Response so callers can inspect StatusCode
+// and friends without importing net/http themselves.
+type Response struct {
+	*http.Response
+}
+
+// RequestOptions configures a single Client.Request call. At most one of
+// JsonBody or RawBody should be set.
+type RequestOptions struct {
+	// JsonBody, if set, is encoded as the request body with a
+	// Content-Type: application/json header.
+	JsonBody interface{}
+	// RawBody, if set, is sent as the request body as-is; callers are
+	// responsible for setting an appropriate Content-Type via Headers.
+	RawBody io.Reader
+	// Headers, if set, are applied to the request after the body-derived
+	// Content-Type, so callers can override it.
+	Headers *map[string]string
+	// QueryParams are encoded into the request URL's query string.
+	QueryParams map[string]string
+}
+
+// ListOptions paginates List-style endpoints.
+type ListOptions struct {
+	Page    int
+	PerPage int
+}
+
+func (o *ListOptions) toQueryParamsMap() map[string]string {
+	params := map[string]string{}
+	if o == nil {
+		return params
+	}
+	if o.Page != 0 {
+		params["page"] = strconv.Itoa(o.Page)
+	}
+	if o.PerPage != 0 {
+		params["per_page"] = strconv.Itoa(o.PerPage)
+	}
+	return params
+}
+
+// Request issues an HTTP request against path (relative to BaseURL),
+// decoding a JSON response body into v when v is non-nil. It returns a
+// non-nil error for both transport failures and non-2xx responses; on a
+// non-2xx response the returned *Response is still populated so callers can
+// inspect the status code (e.g. to distinguish a retryable 5xx from a
+// permanent 4xx).
+func (c *Client) Request(ctx context.Context, method, path string, options *RequestOptions, v interface{}) (*Response, error) {
+	u, err := c.BaseURL.Parse(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil && len(options.QueryParams) > 0 {
+		q := u.Query()
+		for key, value := range options.QueryParams {
+			q.Set(key, value)
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	var body io.Reader
+	isJSON := false
+	if options != nil {
+		switch {
+		case options.RawBody != nil:
+			body = options.RawBody
+		case options.JsonBody != nil:
+			buf := &bytes.Buffer{}
+			if err := json.NewEncoder(buf).Encode(options.JsonBody); err != nil {
+				return nil, err
+			}
+			body = buf
+			isJSON = true
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if options != nil && options.Headers != nil {
+		for key, value := range *options.Headers {
+			req.Header.Set(key, value)
+		}
+	}
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+
+	httpResp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	resp := &Response{Response: httpResp}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return resp, fmt.Errorf("request failed with status %d", httpResp.StatusCode)
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(httpResp.Body).Decode(v); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
@@ -1,18 +1,19 @@
 package bitballoon
 
 import (
-	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"errors"
-	"io"
-	"io/ioutil"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,6 +23,138 @@ var (
 
 type SitesService struct {
 	client *Client
+
+	// UploadConcurrency caps the number of files deployDir uploads at once.
+	// Zero means defaultUploadConcurrency.
+	UploadConcurrency int
+
+	// Compression selects whether uploaded files and zip bodies are
+	// gzip-encoded before they're sent. Defaults to CompressionOff.
+	Compression CompressionMode
+
+	// CompressionLevel is passed to gzip.NewWriterLevel when Compression is
+	// enabled. Zero means gzip.DefaultCompression.
+	CompressionLevel int
+
+	// PreDeployHooks run once deployDir has walked Site.Dir but before any
+	// file is hashed, letting hooks add, remove, or rewrite entries in the
+	// HookContext's Files manifest (relPath -> local filesystem path).
+	PreDeployHooks []Hook
+	// PostDeployHooks run after the site update PUT that registers the
+	// manifest succeeds, before any file content is uploaded.
+	PostDeployHooks []Hook
+	// PreUploadHooks run before the upload worker pool starts, scoped to
+	// just the files the server reported as missing.
+	PreUploadHooks []Hook
+	// PostUploadHooks run after every required file has uploaded
+	// successfully.
+	PostUploadHooks []Hook
+	// OnStateChangeHooks run from WaitForReady each time the polled
+	// site.State changes.
+	OnStateChangeHooks []Hook
+}
+
+// Hook lets callers observe or alter a deploy in flight, or abort it by
+// returning a non-nil error, without forking the client. Modeled on the
+// append-only hook-slice pattern: register by appending to the relevant
+// SitesService.*Hooks slice.
+type Hook func(*Site, *HookContext) error
+
+// HookContext carries the state a Hook may inspect or mutate. Which fields
+// are populated depends on which hook slice is firing: the deploy/upload
+// hooks see Files, OnStateChangeHooks sees PreviousState/State.
+type HookContext struct {
+	// Files maps the relative path a file will be deployed/uploaded under
+	// to its source path on disk. Hooks may add, delete, or repoint entries
+	// to compose behaviors like stripping dotfiles, injecting a generated
+	// _redirects file, or rewriting paths before they reach the server.
+	Files map[string]string
+
+	PreviousState string
+	State         string
+}
+
+func runHooks(hooks []Hook, site *Site, hc *HookContext) error {
+	for _, hook := range hooks {
+		if err := hook(site, hc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CompressionMode controls whether SitesService gzip-encodes upload bodies.
+type CompressionMode int
+
+const (
+	// CompressionOff sends every body uncompressed.
+	CompressionOff CompressionMode = iota
+	// CompressionGzip gzips every body regardless of file type.
+	CompressionGzip
+	// CompressionAuto gzips everything except files whose extension is
+	// already compressed (png, jpg, woff2, etc).
+	CompressionAuto
+)
+
+// incompressibleExtensions lists file extensions CompressionAuto skips
+// because the format is already compressed and gzipping it just burns CPU
+// for no size benefit.
+var incompressibleExtensions = map[string]bool{
+	".png":   true,
+	".jpg":   true,
+	".jpeg":  true,
+	".gif":   true,
+	".webp":  true,
+	".woff":  true,
+	".woff2": true,
+	".zip":   true,
+	".gz":    true,
+}
+
+func (s *SitesService) compressionEnabled(relPath string) bool {
+	switch s.Compression {
+	case CompressionGzip:
+		return true
+	case CompressionAuto:
+		return !incompressibleExtensions[strings.ToLower(filepath.Ext(relPath))]
+	default:
+		return false
+	}
+}
+
+func (s *SitesService) compressionLevel() int {
+	if s.CompressionLevel != 0 {
+		return s.CompressionLevel
+	}
+	return gzip.DefaultCompression
+}
+
+const (
+	defaultUploadConcurrency = 4
+	maxUploadAttempts        = 3
+	uploadBackoffBase        = 500 * time.Millisecond
+	uploadBackoffMax         = 4 * time.Second
+)
+
+// UploadStatus describes the lifecycle of a single file upload, reported via
+// ProgressEvent.
+type UploadStatus int
+
+const (
+	UploadStarted UploadStatus = iota
+	UploadRetrying
+	UploadDone
+	UploadFailed
+)
+
+// ProgressEvent is reported to Site.Progress, if set, as deployDir uploads
+// files to the site.
+type ProgressEvent struct {
+	Path          string
+	BytesUploaded int64
+	TotalBytes    int64
+	Status        UploadStatus
+	Err           error
 }
 
 type Site struct {
@@ -48,7 +181,15 @@ type Site struct {
 	Zip string
 	Dir string
 
+	// Progress, if set, is called from deployDir's upload workers as each
+	// file's upload starts, retries, finishes, or fails.
+	Progress func(ProgressEvent)
+
 	client *Client
+
+	deadlineMu     sync.Mutex
+	pollDeadline   *cancelTimer
+	uploadDeadline *cancelTimer
 }
 
 type DeployInfo struct {
@@ -65,22 +206,87 @@ type siteUpdate struct {
 	Files             *map[string]string `json:"files"`
 }
 
+// cancelTimer closes a channel once a deadline fires, modeled on the
+// deadlineTimer used by netstack's gonet package. Blocking operations select
+// on cancel() alongside their real work so a deadline aborts them without a
+// race on a shared boolean flag.
+type cancelTimer struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	cancelCh chan struct{}
+}
+
+func newCancelTimer() *cancelTimer {
+	return &cancelTimer{cancelCh: make(chan struct{})}
+}
+
+// cancel returns the channel that is closed once the current deadline fires.
+func (c *cancelTimer) cancel() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cancelCh
+}
+
+// setDeadline arms the timer to close the cancel channel at t. A zero Time
+// disarms it. If the previous deadline already fired (or was already past
+// when set), a fresh cancel channel is created so earlier waiters aren't
+// resurrected by the new deadline.
+func (c *cancelTimer) setDeadline(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timer != nil && !c.timer.Stop() {
+		c.cancelCh = make(chan struct{})
+	}
+
+	// An already-past deadline closes the channel without arming a timer.
+	select {
+	case <-c.cancelCh:
+		c.cancelCh = make(chan struct{})
+	default:
+	}
+
+	if t.IsZero() {
+		c.timer = nil
+		return
+	}
+
+	cancelCh := c.cancelCh
+	timeout := time.Until(t)
+	if timeout <= 0 {
+		close(cancelCh)
+		return
+	}
+
+	c.timer = time.AfterFunc(timeout, func() {
+		close(cancelCh)
+	})
+}
+
 func (s *SitesService) Get(id string) (*Site, *Response, error) {
+	return s.GetWithContext(context.Background(), id)
+}
+
+func (s *SitesService) GetWithContext(ctx context.Context, id string) (*Site, *Response, error) {
 	site := &Site{Id: id, client: s.client}
-	resp, err := site.refresh()
+	resp, err := site.refreshWithContext(ctx)
 
 	return site, resp, err
 }
 
 func (s *SitesService) List(options *ListOptions) ([]Site, *Response, error) {
+	return s.ListWithContext(context.Background(), options)
+}
+
+func (s *SitesService) ListWithContext(ctx context.Context, options *ListOptions) ([]Site, *Response, error) {
 	sites := new([]Site)
 
 	reqOptions := &RequestOptions{QueryParams: options.toQueryParamsMap()}
 
-	resp, err := s.client.Request("GET", "/sites", reqOptions, sites)
+	resp, err := s.client.Request(ctx, "GET", "/sites", reqOptions, sites)
 
-	for _, site := range(*sites) {
-		site.client = s.client
+	for i := range *sites {
+		(*sites)[i].client = s.client
 	}
 
 	return *sites, resp, err
@@ -91,26 +297,70 @@ func (site *Site) apiPath() string {
 }
 
 func (site *Site) refresh() (*Response, error) {
+	return site.refreshWithContext(context.Background())
+}
+
+func (site *Site) refreshWithContext(ctx context.Context) (*Response, error) {
 	if site.Id == "" {
 		return nil, errors.New("Cannot fetch site without an ID")
 	}
-	return site.client.Request("GET", site.apiPath(), nil, site)
+	return site.client.Request(ctx, "GET", site.apiPath(), nil, site)
+}
+
+// pollCancel lazily initializes and returns the cancelTimer backing
+// WaitForReady's deadline.
+func (site *Site) pollCancel() *cancelTimer {
+	site.deadlineMu.Lock()
+	defer site.deadlineMu.Unlock()
+	if site.pollDeadline == nil {
+		site.pollDeadline = newCancelTimer()
+	}
+	return site.pollDeadline
+}
+
+// uploadCancel lazily initializes and returns the cancelTimer backing
+// deployDir/deployZip's upload deadline.
+func (site *Site) uploadCancel() *cancelTimer {
+	site.deadlineMu.Lock()
+	defer site.deadlineMu.Unlock()
+	if site.uploadDeadline == nil {
+		site.uploadDeadline = newCancelTimer()
+	}
+	return site.uploadDeadline
+}
+
+// SetDeadline arms a deadline after which WaitForReady gives up polling and
+// returns a timeout error. A zero Time disarms it. WaitForReady arms its own
+// deadline from its timeout argument when it starts, replacing any deadline
+// set before the call, and disarms it on return, so SetDeadline only takes
+// effect while a WaitForReady call is in progress.
+func (site *Site) SetDeadline(t time.Time) {
+	site.pollCancel().setDeadline(t)
+}
+
+// SetUploadDeadline arms a deadline after which an in-flight deployDir or
+// deployZip upload is aborted. A zero Time disarms it.
+func (site *Site) SetUploadDeadline(t time.Time) {
+	site.uploadCancel().setDeadline(t)
 }
 
 func (site *Site) Update() (*Response, error) {
+	return site.UpdateWithContext(context.Background())
+}
 
+func (site *Site) UpdateWithContext(ctx context.Context) (*Response, error) {
 	if site.Zip != "" {
-		return site.deployZip()
-	} else {
-		return site.deployDir()
+		return site.deployZip(ctx)
 	}
 
-	options := &RequestOptions{JsonBody: site.mutableParams()}
-
-	return site.client.Request("PUT", site.apiPath(), options, site)
+	return site.deployDir(ctx)
 }
 
 func (site *Site) WaitForReady(timeout time.Duration) error {
+	return site.WaitForReadyWithContext(context.Background(), timeout)
+}
+
+func (site *Site) WaitForReadyWithContext(ctx context.Context, timeout time.Duration) error {
 	if site.State == "current" {
 		return nil
 	}
@@ -119,66 +369,121 @@ func (site *Site) WaitForReady(timeout time.Duration) error {
 		timeout = defaultTimeout
 	}
 
-	timedOut := false
-	time.AfterFunc(timeout*time.Second, func() {
-		timedOut = true
-	})
+	site.SetDeadline(time.Now().Add(timeout * time.Second))
+	defer site.SetDeadline(time.Time{})
 
-	done := make(chan error)
+	cancelCh := site.pollCancel().cancel()
+	previousState := site.State
 
-	go func() {
-		for {
-			time.Sleep(1 * time.Second)
-
-			if timedOut {
-				done <- errors.New("Timeout while waiting for processing")
-				break
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-cancelCh:
+			return errors.New("Timeout while waiting for processing")
+		case <-time.After(1 * time.Second):
+		}
 
-			site, _, err := site.client.Sites.Get(site.Id)
-			if site != nil {
-				fmt.Println("Site state is now: ", site.State)
-			}
-			if err != nil || (site != nil && site.State == "current") {
-				done <- err
-				break
+		refreshed, _, err := site.client.Sites.GetWithContext(ctx, site.Id)
+		if refreshed != nil {
+			if refreshed.State != previousState {
+				if hookErr := runHooks(site.client.Sites.OnStateChangeHooks, site, &HookContext{PreviousState: previousState, State: refreshed.State}); hookErr != nil {
+					return hookErr
+				}
+				previousState = refreshed.State
 			}
 		}
-	}()
+		if err != nil {
+			return err
+		}
+		if refreshed != nil && refreshed.State == "current" {
+			return nil
+		}
+	}
+}
 
-	err := <-done
-	return err
+// fileManifestEntry is the sha1 and size of one file discovered under
+// Site.Dir, computed in a single streaming pass so deployDir never holds a
+// whole file in memory just to hash it.
+type fileManifestEntry struct {
+	sha  string
+	size int64
 }
 
-func (site *Site) deployDir() (*Response, error) {
-	files := map[string]string{}
+func hashFile(path string) (fileManifestEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileManifestEntry{}, err
+	}
+	defer file.Close()
 
-	err := filepath.Walk(site.Dir, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() == false {
-			rel, err := filepath.Rel(site.Dir, path)
-			if err != nil {
-				return err
-			}
+	hash := sha1.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fileManifestEntry{}, err
+	}
 
-			if strings.HasPrefix(rel, ".") || strings.Contains(rel, "/.") {
-				return nil
-			}
+	return fileManifestEntry{sha: hex.EncodeToString(hash.Sum(nil)), size: size}, nil
+}
 
-			sha := sha1.New()
-			data, err := ioutil.ReadFile(path)
+func (site *Site) deployDir(ctx context.Context) (*Response, error) {
+	uploadCancel := site.uploadCancel().cancel()
 
-			if err != nil {
-				return err
-			}
+	localPaths := map[string]string{}
+
+	err := filepath.Walk(site.Dir, func(walkPath string, info os.FileInfo, err error) error {
+		select {
+		case <-uploadCancel:
+			return errors.New("Upload canceled")
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
 
-			sha.Write(data)
+		rel, err := filepath.Rel(site.Dir, walkPath)
+		if err != nil {
+			return err
+		}
 
-			files[rel] = hex.EncodeToString(sha.Sum(nil))
+		if strings.HasPrefix(rel, ".") || strings.Contains(rel, "/.") {
+			return nil
 		}
 
+		localPaths[rel] = walkPath
+
 		return nil
 	})
 
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runHooks(site.client.Sites.PreDeployHooks, site, &HookContext{Files: localPaths}); err != nil {
+		return nil, err
+	}
+
+	manifest := map[string]fileManifestEntry{}
+	for rel, walkPath := range localPaths {
+		entry, err := hashFile(walkPath)
+		if err != nil {
+			return nil, err
+		}
+		manifest[rel] = entry
+	}
+
+	files := map[string]string{}
+	for rel, entry := range manifest {
+		files[rel] = entry.sha
+	}
+
 	options := &RequestOptions{
 		JsonBody: &siteUpdate{
 			Name:              site.Name,
@@ -190,70 +495,380 @@ func (site *Site) deployDir() (*Response, error) {
 	}
 
 	deployInfo := new(DeployInfo)
-	resp, err := site.client.Request("PUT", site.apiPath(), options, deployInfo)
+	resp, err := site.client.Request(ctx, "PUT", site.apiPath(), options, deployInfo)
 
 	if err != nil {
 		return resp, err
 	}
 
-	lookup := map[string]bool{}
+	if err := runHooks(site.client.Sites.PostDeployHooks, site, &HookContext{Files: localPaths}); err != nil {
+		return resp, err
+	}
 
+	required := map[string]bool{}
 	for _, sha := range deployInfo.Required {
-		lookup[sha] = true
+		required[sha] = true
+	}
+
+	requiredPaths := map[string]string{}
+	for rel, entry := range manifest {
+		if required[entry.sha] {
+			requiredPaths[rel] = localPaths[rel]
+		}
+	}
+
+	if err := runHooks(site.client.Sites.PreUploadHooks, site, &HookContext{Files: requiredPaths}); err != nil {
+		return resp, err
+	}
+
+	if len(requiredPaths) == 0 {
+		return resp, nil
+	}
+
+	var totalBytes int64
+	for rel, localPath := range requiredPaths {
+		if entry, ok := manifest[rel]; ok {
+			totalBytes += entry.size
+			continue
+		}
+		if info, err := os.Stat(localPath); err == nil {
+			totalBytes += info.Size()
+		}
+	}
+
+	if err := site.uploadFiles(ctx, uploadCancel, requiredPaths, totalBytes); err != nil {
+		return resp, err
+	}
+
+	if err := runHooks(site.client.Sites.PostUploadHooks, site, &HookContext{Files: requiredPaths}); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// uploadFiles pushes paths (relPath -> local filesystem path, reflecting any
+// rewrite a PreUploadHooks hook made) to the site over a bounded worker
+// pool, retrying each file with exponential backoff and reporting progress
+// via Site.Progress. It returns the first terminal error encountered, after
+// which remaining workers stop picking up new files.
+func (site *Site) uploadFiles(ctx context.Context, uploadCancel <-chan struct{}, paths map[string]string, totalBytes int64) error {
+	concurrency := site.client.Sites.UploadConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultUploadConcurrency
+	}
+	if concurrency > len(paths) {
+		concurrency = len(paths)
 	}
 
-	for path, sha := range files {
-		if lookup[sha] == true {
-			file, _ := os.Open(filepath.Join(site.Dir, path))
-			defer file.Close()
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
 
-			options = &RequestOptions{
-				RawBody: file,
-				Headers: &map[string]string{"Content-Type": "application/octet-stream"},
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		for relPath := range paths {
+			select {
+			case jobs <- relPath:
+			case <-workerCtx.Done():
+				return
 			}
-			fmt.Println("Uploading %s", path)
-			resp, err = site.client.Request("PUT", filepath.Join(site.apiPath(), "files", path), options, nil)
-			if err != nil {
-				fmt.Println("Error", err)
-				return resp, err
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		uploaded int64
+		firstErr error
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for relPath := range jobs {
+				size, err := site.uploadFileWithRetry(workerCtx, uploadCancel, relPath, paths[relPath])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("uploading %s: %w", relPath, err)
+						stop()
+					}
+					mu.Unlock()
+					continue
+				}
+
+				mu.Lock()
+				uploaded += size
+				site.reportProgress(ProgressEvent{Path: relPath, BytesUploaded: uploaded, TotalBytes: totalBytes, Status: UploadDone})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// retryableUploadError marks an upload failure as transient (a 5xx response
+// or a network/transport error) so uploadFileWithRetry backs off and retries
+// it, instead of retrying a permanent 4xx that will never succeed.
+type retryableUploadError struct {
+	err error
+}
+
+func (e *retryableUploadError) Error() string { return e.err.Error() }
+func (e *retryableUploadError) Unwrap() error { return e.err }
+
+// uploadFileWithRetry uploads a single file, retrying transient failures
+// with exponential backoff, and returns its size on success.
+func (site *Site) uploadFileWithRetry(ctx context.Context, uploadCancel <-chan struct{}, relPath, localPath string) (int64, error) {
+	site.reportProgress(ProgressEvent{Path: relPath, Status: UploadStarted})
+
+	backoff := uploadBackoffBase
+	var lastErr error
+
+	for attempt := 0; attempt < maxUploadAttempts; attempt++ {
+		if attempt > 0 {
+			site.reportProgress(ProgressEvent{Path: relPath, Status: UploadRetrying, Err: lastErr})
+
+			select {
+			case <-time.After(backoff):
+			case <-uploadCancel:
+				return 0, errors.New("Upload canceled")
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+
+			backoff *= 2
+			if backoff > uploadBackoffMax {
+				backoff = uploadBackoffMax
 			}
 		}
+
+		select {
+		case <-uploadCancel:
+			return 0, errors.New("Upload canceled")
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		default:
+		}
+
+		size, err := site.uploadFile(ctx, relPath, localPath)
+		if err == nil {
+			return size, nil
+		}
+
+		lastErr = err
+
+		var retryable *retryableUploadError
+		if !errors.As(err, &retryable) {
+			break
+		}
 	}
 
-	return resp, err
+	site.reportProgress(ProgressEvent{Path: relPath, Status: UploadFailed, Err: lastErr})
+
+	return 0, lastErr
 }
 
-func (site *Site) deployZip() (*Response, error) {
-	zipPath, err := filepath.Abs(site.Zip)
+func (site *Site) uploadFile(ctx context.Context, relPath, localPath string) (int64, error) {
+	file, err := os.Open(localPath)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	headers := map[string]string{"Content-Type": "application/octet-stream"}
 
-	fileWriter, err := writer.CreateFormFile("zip", filepath.Base(zipPath))
-	fileReader, err := os.Open(zipPath)
-	defer fileReader.Close()
+	var body io.Reader = file
+	if site.client.Sites.compressionEnabled(relPath) {
+		pr, pw := io.Pipe()
+
+		go func() {
+			gz, err := gzip.NewWriterLevel(pw, site.client.Sites.compressionLevel())
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			pw.CloseWithError(func() error {
+				if _, err := io.Copy(gz, file); err != nil {
+					return err
+				}
+				return gz.Close()
+			}())
+		}()
+
+		body = pr
+		headers["Content-Encoding"] = "gzip"
+	}
+
+	options := &RequestOptions{
+		RawBody: body,
+		Headers: &headers,
+	}
 
+	resp, err := site.client.Request(ctx, "PUT", filepath.Join(site.apiPath(), "files", relPath), options, nil)
+	if err != nil {
+		if resp == nil || resp.StatusCode >= 500 {
+			return 0, &retryableUploadError{err}
+		}
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+func (site *Site) reportProgress(event ProgressEvent) {
+	if site.Progress != nil {
+		site.Progress(event)
+	}
+}
+
+// cancelableReader wraps r so the blocking io.Copy reading from it observes
+// an upload deadline/cancellation between reads, the same way deployDir's
+// per-file loop does, instead of ignoring it for the life of the copy.
+type cancelableReader struct {
+	ctx      context.Context
+	cancelCh <-chan struct{}
+	r        io.Reader
+}
+
+func (cr *cancelableReader) Read(p []byte) (int, error) {
+	select {
+	case <-cr.cancelCh:
+		return 0, errors.New("Upload canceled")
+	case <-cr.ctx.Done():
+		return 0, cr.ctx.Err()
+	default:
+	}
+	return cr.r.Read(p)
+}
+
+func (site *Site) deployZip(ctx context.Context) (*Response, error) {
+	zipPath, err := filepath.Abs(site.Zip)
 	if err != nil {
 		return nil, err
 	}
-	io.Copy(fileWriter, fileReader)
 
-	for key, value := range *site.mutableParams() {
-		writer.WriteField(key, value)
+	file, err := os.Open(zipPath)
+	if err != nil {
+		return nil, err
 	}
+	defer file.Close()
 
-	err = writer.Close()
+	info, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
 
-	contentType := "multipar/form-data; boundary=" + writer.Boundary()
-	options := &RequestOptions{RawBody: body, Headers: &map[string]string{"Content-Type": contentType}}
+	return site.deployZipReader(ctx, file, filepath.Base(zipPath), info.Size())
+}
+
+// DeployZipReader deploys a zip read from r, skipping the filesystem
+// entirely so callers can deploy zips assembled in memory or piped from
+// another process. size is informational only, reported to Site.Progress;
+// pass 0 if unknown.
+func (site *Site) DeployZipReader(r io.Reader, size int64) (*Response, error) {
+	return site.DeployZipReaderWithContext(context.Background(), r, size)
+}
+
+func (site *Site) DeployZipReaderWithContext(ctx context.Context, r io.Reader, size int64) (*Response, error) {
+	return site.deployZipReader(ctx, r, "site.zip", size)
+}
+
+// deployZipReader streams r into a multipart body through an io.Pipe, so the
+// zip never sits fully in memory the way a bytes.Buffer-backed body would.
+// A goroutine drives the multipart writer (and, if enabled, a gzip writer in
+// front of it) against the pipe's write end while the PUT reads from the
+// other end.
+func (site *Site) deployZipReader(ctx context.Context, r io.Reader, filename string, size int64) (*Response, error) {
+	pr, pw := io.Pipe()
+
+	uploadCancel := site.uploadCancel().cancel()
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-uploadCancel:
+			pr.CloseWithError(errors.New("Upload canceled"))
+			cancel()
+		case <-uploadCtx.Done():
+		}
+	}()
+
+	compress := site.client.Sites.compressionEnabled(filename)
 
-	return site.client.Request("PUT", site.apiPath(), options, nil)
+	var target io.Writer = pw
+	var gz *gzip.Writer
+	if compress {
+		var err error
+		gz, err = gzip.NewWriterLevel(pw, site.client.Sites.compressionLevel())
+		if err != nil {
+			pw.Close()
+			return nil, err
+		}
+		target = gz
+	}
+
+	writer := multipart.NewWriter(target)
+	boundary := writer.Boundary()
+
+	site.reportProgress(ProgressEvent{Path: filename, TotalBytes: size, Status: UploadStarted})
+
+	go func() {
+		pw.CloseWithError(func() error {
+			fileWriter, err := writer.CreateFormFile("zip", filename)
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(fileWriter, &cancelableReader{ctx: uploadCtx, cancelCh: uploadCancel, r: r}); err != nil {
+				return err
+			}
+
+			for key, value := range *site.mutableParams() {
+				if err := writer.WriteField(key, value); err != nil {
+					return err
+				}
+			}
+
+			if err := writer.Close(); err != nil {
+				return err
+			}
+
+			if gz != nil {
+				return gz.Close()
+			}
+
+			return nil
+		}())
+	}()
+
+	headers := map[string]string{"Content-Type": "multipart/form-data; boundary=" + boundary}
+	if compress {
+		headers["Content-Encoding"] = "gzip"
+	}
+	options := &RequestOptions{RawBody: pr, Headers: &headers}
+
+	resp, err := site.client.Request(uploadCtx, "PUT", site.apiPath(), options, nil)
+	if err != nil {
+		site.reportProgress(ProgressEvent{Path: filename, TotalBytes: size, Status: UploadFailed, Err: err})
+	} else {
+		site.reportProgress(ProgressEvent{Path: filename, BytesUploaded: size, TotalBytes: size, Status: UploadDone})
+	}
+
+	return resp, err
 }
 
 func (site *Site) mutableParams() *map[string]string {
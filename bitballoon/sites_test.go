@@ -0,0 +1,275 @@
+package bitballoon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+
+	baseURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+
+	client := &Client{client: server.Client(), BaseURL: baseURL}
+	client.Sites = &SitesService{client: client}
+
+	return client
+}
+
+func TestCancelTimerClosesChannelAfterDeadline(t *testing.T) {
+	c := newCancelTimer()
+	c.setDeadline(time.Now().Add(10 * time.Millisecond))
+
+	select {
+	case <-c.cancel():
+	case <-time.After(time.Second):
+		t.Fatal("cancel channel never closed")
+	}
+}
+
+func TestCancelTimerZeroDisarms(t *testing.T) {
+	c := newCancelTimer()
+	c.setDeadline(time.Now().Add(10 * time.Millisecond))
+	c.setDeadline(time.Time{})
+
+	select {
+	case <-c.cancel():
+		t.Fatal("cancel channel closed after deadline was disarmed")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestUploadFileWithRetryRetriesServerErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := testClient(t, server)
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(localPath, []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+
+	site := &Site{Id: "site1", client: client}
+
+	size, err := site.uploadFileWithRetry(context.Background(), make(chan struct{}), "index.html", localPath)
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if size != int64(len("hello")) {
+		t.Fatalf("unexpected size %d", size)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestUploadFileWithRetryFailsFastOn4xx(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := testClient(t, server)
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(localPath, []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+
+	site := &Site{Id: "site1", client: client}
+
+	if _, err := site.uploadFileWithRetry(context.Background(), make(chan struct{}), "index.html", localPath); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected exactly 1 attempt (no retry on 4xx), got %d", got)
+	}
+}
+
+func TestUploadFileWithRetryHonorsCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := testClient(t, server)
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(localPath, []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+
+	site := &Site{Id: "site1", client: client}
+
+	cancelCh := make(chan struct{})
+	close(cancelCh)
+
+	start := time.Now()
+	if _, err := site.uploadFileWithRetry(context.Background(), cancelCh, "index.html", localPath); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > uploadBackoffBase {
+		t.Fatalf("expected cancellation to abort before backoff elapsed, took %s", elapsed)
+	}
+}
+
+func TestDeployDirHonorsPreUploadHooksRewrite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+
+	outsideDir := t.TempDir()
+	rewritten := filepath.Join(outsideDir, "_redirects")
+	if err := os.WriteFile(rewritten, []byte("/* /index.html 200"), 0644); err != nil {
+		t.Fatalf("writing fixture file: %v", err)
+	}
+
+	var uploadedPath string
+	var uploadedBody []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sites/site1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&DeployInfo{Id: "site1", DeployId: "deploy1", Required: []string{"does-not-match-anything"}})
+	})
+	mux.HandleFunc("/sites/site1/files/", func(w http.ResponseWriter, r *http.Request) {
+		uploadedPath = r.URL.Path
+		uploadedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := testClient(t, server)
+	client.Sites.PreUploadHooks = []Hook{
+		func(site *Site, hc *HookContext) error {
+			hc.Files["_redirects"] = rewritten
+			return nil
+		},
+	}
+
+	site := &Site{Id: "site1", Dir: dir, client: client}
+
+	if _, err := site.deployDir(context.Background()); err != nil {
+		t.Fatalf("deployDir failed: %v", err)
+	}
+
+	if uploadedPath != "/sites/site1/files/_redirects" {
+		t.Fatalf("expected hook-added file to be uploaded, got path %q", uploadedPath)
+	}
+	if string(uploadedBody) != "/* /index.html 200" {
+		t.Fatalf("uploaded body doesn't match hook-rewritten source file, got %q", uploadedBody)
+	}
+}
+
+func TestDeployZipReaderRoundTrip(t *testing.T) {
+	var receivedContentType string
+	var receivedFormName string
+	var received []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedContentType = r.Header.Get("Content-Type")
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("request is not multipart: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Errorf("reading multipart part: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		receivedFormName = part.FormName()
+		received, _ = io.ReadAll(part)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := testClient(t, server)
+	site := &Site{Id: "site1", client: client}
+
+	payload := []byte("fake zip contents")
+	if _, err := site.DeployZipReader(bytes.NewReader(payload), int64(len(payload))); err != nil {
+		t.Fatalf("DeployZipReader failed: %v", err)
+	}
+
+	if !strings.Contains(receivedContentType, "multipart/form-data") {
+		t.Fatalf("unexpected Content-Type %q", receivedContentType)
+	}
+	if receivedFormName != "zip" {
+		t.Fatalf("unexpected form field name %q", receivedFormName)
+	}
+	if string(received) != string(payload) {
+		t.Fatalf("uploaded zip contents mismatch, got %q", received)
+	}
+}
+
+// slowReader trickles data out one byte at a time with a short delay, so a
+// deadline/cancellation set well before the whole payload is read has time
+// to fire mid-copy instead of racing a fast in-memory copy.
+type slowReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	time.Sleep(5 * time.Millisecond)
+	n := copy(p, r.data[r.pos:r.pos+1])
+	r.pos += n
+	return n, nil
+}
+
+func TestSetUploadDeadlineAbortsZipDeploy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := testClient(t, server)
+	site := &Site{Id: "site1", client: client}
+	site.SetUploadDeadline(time.Now().Add(20 * time.Millisecond))
+
+	src := &slowReader{data: bytes.Repeat([]byte("a"), 50)}
+
+	if _, err := site.DeployZipReader(src, int64(len(src.data))); err == nil {
+		t.Fatal("expected upload deadline to abort the deploy")
+	}
+}